@@ -36,6 +36,7 @@ package cgc
 
 import (
 	"context"
+	"errors"
 	"io"
 
 	"github.com/LK4D4/joincontext"
@@ -92,10 +93,10 @@ func NewBuffered(bufferLength uint) Executor {
 func (e Executor) RunLoop(ctx context.Context) error {
 	for {
 		err := e.RunOnce(ctx)
-		if err == io.EOF {
+		if errors.Is(err, io.EOF) {
 			return nil
 		}
-		if err == context.Canceled {
+		if err != nil {
 			return err
 		}
 	}
@@ -112,10 +113,10 @@ func (e Executor) RunLoop(ctx context.Context) error {
 func (e Executor) RunOnce(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
-		return context.Canceled
+		return &SubmitError{Op: "RunOnce", Side: SideCallee, Cause: ctx.Err()}
 	case r, ok := <-e:
 		if !ok {
-			return io.EOF
+			return &SubmitError{Op: "RunOnce", Side: SideCallee, Cause: io.EOF}
 		}
 		RunOneRequest(ctx, r)
 		return nil
@@ -130,7 +131,7 @@ func (e Executor) Submit(ctx context.Context, f Func) (interface{}, error) {
 	resultChan := make(chan *result, 1)
 	select {
 	case <-ctx.Done():
-		return nil, context.Canceled
+		return nil, &SubmitError{Op: "Submit", Side: SideCaller, Cause: ctx.Err()}
 	case e <- &Request{
 		Func:    f,
 		Context: ctx,
@@ -152,7 +153,7 @@ func (e Executor) Submit(ctx context.Context, f Func) (interface{}, error) {
 func (e Executor) SubmitNoWait(ctx context.Context, f Func) error {
 	select {
 	case <-ctx.Done():
-		return context.Canceled
+		return &SubmitError{Op: "SubmitNoWait", Side: SideCaller, Cause: ctx.Err()}
 	case e <- &Request{
 		Func:    f,
 		Context: ctx,
@@ -25,6 +25,7 @@ package cgc
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 )
@@ -85,7 +86,7 @@ func TestCancel(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 	err := ex.RunLoop(ctx)
-	if err != context.Canceled {
+	if !errors.Is(err, context.Canceled) {
 		t.Fail()
 	}
 }
@@ -98,11 +99,11 @@ func TestSubmitCancel(t *testing.T) {
 		t.Fail()
 		return nil, nil
 	})
-	if res != nil || err != context.Canceled {
+	if res != nil || !errors.Is(err, context.Canceled) {
 		t.Fail()
 	}
 	err = ex.RunOnce(ctx)
-	if err != context.Canceled {
+	if !errors.Is(err, context.Canceled) {
 		t.Fail()
 	}
 }
@@ -115,11 +116,11 @@ func TestSubmitNoWaitCancel(t *testing.T) {
 		t.Fail()
 		return nil, nil
 	})
-	if err != context.Canceled {
+	if !errors.Is(err, context.Canceled) {
 		t.Fail()
 	}
 	err = ex.RunOnce(ctx)
-	if err != context.Canceled {
+	if !errors.Is(err, context.Canceled) {
 		t.Fail()
 	}
 }
@@ -0,0 +1,57 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import (
+	"context"
+	"time"
+)
+
+// SubmitWithTimeout submits a request to the executor and waits for the
+// result, failing with context.DeadlineExceeded if the request is not
+// received within timeout. Once received, f runs with a context that carries
+// the timeout, and whatever f returns is passed back as-is; Submit does not
+// stop waiting for f when the timeout expires.
+//
+// This function should be called from the caller goroutine, either ctx, the
+// timeout, or the context at the callee goroutine may cancel the request.
+func (e Executor) SubmitWithTimeout(ctx context.Context, timeout time.Duration, f Func) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return e.Submit(ctx, f)
+}
+
+// SubmitWithDeadline submits a request to the executor and waits for the
+// result, failing with context.DeadlineExceeded if the request is not
+// received before deadline. Once received, f runs with a context that
+// carries the deadline, and whatever f returns is passed back as-is; Submit
+// does not stop waiting for f when the deadline passes.
+//
+// This function should be called from the caller goroutine, either ctx, the
+// deadline, or the context at the callee goroutine may cancel the request.
+func (e Executor) SubmitWithDeadline(ctx context.Context, deadline time.Time, f Func) (interface{}, error) {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	return e.Submit(ctx, f)
+}
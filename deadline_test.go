@@ -0,0 +1,64 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSubmitWithTimeout(t *testing.T) {
+	ex := New()
+	res, err := ex.SubmitWithTimeout(context.Background(), time.Millisecond, func(ctx context.Context) (interface{}, error) {
+		t.Fail()
+		return nil, nil
+	})
+	if res != nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fail()
+	}
+}
+
+func TestSubmitWithDeadline(t *testing.T) {
+	ex := New()
+	res, err := ex.SubmitWithDeadline(context.Background(), time.Now().Add(time.Millisecond), func(ctx context.Context) (interface{}, error) {
+		t.Fail()
+		return nil, nil
+	})
+	if res != nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fail()
+	}
+}
+
+func TestSubmitWithTimeoutSuccess(t *testing.T) {
+	ex := New()
+	go ex.RunOnce(context.Background())
+	res, err := ex.SubmitWithTimeout(context.Background(), time.Hour, func(ctx context.Context) (interface{}, error) {
+		return 42, nil
+	})
+	if res != 42 || err != nil {
+		t.Fail()
+	}
+}
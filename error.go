@@ -0,0 +1,76 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import "fmt"
+
+// Side identifies which end of a Submit/RunOnce call triggered an abort.
+type Side int
+
+const (
+	// SideCaller means the caller's context ended the call, for example by
+	// cancellation or timeout before the request was received or completed.
+	SideCaller Side = iota
+	// SideCallee means the callee's context, or the closing of the
+	// Executor's channel, ended the call.
+	SideCallee
+)
+
+func (s Side) String() string {
+	switch s {
+	case SideCaller:
+		return "caller"
+	case SideCallee:
+		return "callee"
+	default:
+		return "unknown"
+	}
+}
+
+// SubmitError is returned by Submit, SubmitNoWait, RunOnce, and RunLoop when
+// a request is aborted rather than completed. It identifies which side
+// triggered the abort and preserves the underlying cause, so that
+// errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded),
+// and errors.Is(err, io.EOF) keep working as they would against the bare
+// sentinel.
+type SubmitError struct {
+	// Op is the name of the operation that returned the error, e.g.
+	// "Submit" or "RunOnce".
+	Op string
+	// Side identifies whether the caller or the callee triggered the abort.
+	Side Side
+	// Cause is the underlying error: context.Canceled,
+	// context.DeadlineExceeded, or io.EOF.
+	Cause error
+}
+
+func (e *SubmitError) Error() string {
+	return fmt.Sprintf("cgc: %s: %s: %v", e.Op, e.Side, e.Cause)
+}
+
+// Unwrap returns Cause, so that errors.Is and errors.As see through a
+// SubmitError to the sentinel it wraps.
+func (e *SubmitError) Unwrap() error {
+	return e.Cause
+}
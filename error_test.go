@@ -0,0 +1,54 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestSubmitErrorIs(t *testing.T) {
+	err := error(&SubmitError{Op: "RunOnce", Side: SideCallee, Cause: io.EOF})
+	if !errors.Is(err, io.EOF) {
+		t.Fail()
+	}
+	if errors.Is(err, context.Canceled) {
+		t.Fail()
+	}
+}
+
+func TestRunOnceClosedReturnsEOF(t *testing.T) {
+	ex := New()
+	close(ex)
+	err := ex.RunOnce(context.Background())
+	if !errors.Is(err, io.EOF) {
+		t.Fail()
+	}
+	var submitErr *SubmitError
+	if !errors.As(err, &submitErr) || submitErr.Side != SideCallee {
+		t.Fail()
+	}
+}
@@ -0,0 +1,103 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Group manages the lifecycle of a set of goroutines that each own an
+// Executor, similar in spirit to errgroup.Group. It bundles the
+// context.WithCancel, sync.WaitGroup, and error plumbing that every
+// long-lived consumer of Executor otherwise has to wire up by hand.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg sync.WaitGroup
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewGroup creates a Group whose root context is derived from ctx. Stopping
+// the Group, or a worker spawned with Go returning an error other than
+// context.Canceled or context.DeadlineExceeded, cancels that root context.
+func NewGroup(ctx context.Context) *Group {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Context returns the Group's root context.
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Go spawns a worker goroutine running f with the Group's context and ex.
+// If ex is nil, a new unbuffered Executor is created for the worker;
+// otherwise ex is passed through as-is, which allows several workers to
+// share one Executor.
+//
+// f is expected to return once ctx is done, for example by calling
+// ex.RunLoop(ctx). The first non-context error returned by any worker
+// cancels the Group and is reported by Wait; context.Canceled and
+// context.DeadlineExceeded are treated as expected shutdown and do not
+// count as failures.
+func (g *Group) Go(ex Executor, f func(ctx context.Context, ex Executor) error) {
+	if ex == nil {
+		ex = New()
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		err := f(g.ctx, ex)
+		if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return
+		}
+		g.mu.Lock()
+		if g.err == nil {
+			g.err = err
+		}
+		g.mu.Unlock()
+		g.cancel()
+	}()
+}
+
+// Stop cancels the Group's root context, signalling every worker spawned
+// with Go to return. Stop is safe to call more than once and from multiple
+// goroutines; only the first call has any effect.
+func (g *Group) Stop() {
+	g.cancel()
+}
+
+// Wait blocks until every worker spawned with Go has returned, then returns
+// the first non-context error reported by any of them, if any.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.err
+}
@@ -0,0 +1,77 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGroupStopWaits(t *testing.T) {
+	g := NewGroup(context.Background())
+	g.Go(nil, func(ctx context.Context, ex Executor) error {
+		return ex.RunLoop(ctx)
+	})
+	g.Stop()
+	g.Stop()
+	if err := g.Wait(); err != nil {
+		t.Fail()
+	}
+}
+
+func TestGroupPropagatesError(t *testing.T) {
+	wantErr := errors.New("boom")
+	g := NewGroup(context.Background())
+	g.Go(nil, func(ctx context.Context, ex Executor) error {
+		return wantErr
+	})
+	if err := g.Wait(); err != wantErr {
+		t.Fail()
+	}
+	select {
+	case <-g.Context().Done():
+	default:
+		t.Fail()
+	}
+}
+
+func TestGroupSharedExecutor(t *testing.T) {
+	ex := New()
+	g := NewGroup(context.Background())
+	g.Go(ex, func(ctx context.Context, ex Executor) error {
+		return ex.RunLoop(ctx)
+	})
+	g.Go(ex, func(ctx context.Context, ex Executor) error {
+		return ex.RunLoop(ctx)
+	})
+	res, err := ex.Submit(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return 42, nil
+	})
+	if res != 42 || err != nil {
+		t.Fail()
+	}
+	g.Stop()
+	g.Wait()
+}
@@ -0,0 +1,123 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import (
+	"context"
+	"sync"
+)
+
+// call tracks a single in-flight, deduplicated submission shared by every
+// waiter that submitted the same key while it was running.
+type call struct {
+	cancel  context.CancelFunc
+	waiters []chan<- *result
+	count   int
+}
+
+// KeyedExecutor wraps an Executor and coalesces concurrent SubmitKeyed calls
+// that share the same key into a single execution, fanning the result back
+// to every waiter. This is useful to avoid redundant work when many
+// goroutines request the same thing from the callee at once.
+type KeyedExecutor struct {
+	Executor Executor
+
+	mu    sync.Mutex
+	calls map[interface{}]*call
+}
+
+// NewKeyed creates a new KeyedExecutor backed by e.
+func NewKeyed(e Executor) *KeyedExecutor {
+	return &KeyedExecutor{
+		Executor: e,
+		calls:    make(map[interface{}]*call),
+	}
+}
+
+// SubmitKeyed submits f under key and waits for the result. If another
+// SubmitKeyed call for the same key is already in flight on this
+// KeyedExecutor, this call does not enqueue a new request; instead it waits
+// for the in-flight call to finish and shares its result.
+//
+// Each waiter may cancel via its own ctx without affecting the others. The
+// context passed to f is only canceled once every waiter sharing the call
+// has had its ctx done, matching the semantics of a single, ungrouped
+// Submit.
+func (k *KeyedExecutor) SubmitKeyed(ctx context.Context, key interface{}, f Func) (interface{}, error) {
+	resultChan := make(chan *result, 1)
+
+	k.mu.Lock()
+	c, ok := k.calls[key]
+	if ok {
+		c.count++
+		c.waiters = append(c.waiters, resultChan)
+		k.mu.Unlock()
+	} else {
+		callCtx, cancel := context.WithCancel(context.Background())
+		c = &call{
+			cancel:  cancel,
+			waiters: []chan<- *result{resultChan},
+			count:   1,
+		}
+		k.calls[key] = c
+		k.mu.Unlock()
+
+		go func() {
+			val, err := k.Executor.Submit(callCtx, f)
+
+			k.mu.Lock()
+			if k.calls[key] == c {
+				delete(k.calls, key)
+			}
+			waiters := c.waiters
+			k.mu.Unlock()
+
+			for _, w := range waiters {
+				w <- &result{val: val, err: err}
+				close(w)
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		k.mu.Lock()
+		c.count--
+		if c.count == 0 {
+			// The call has no waiters left to deliver a result to. Evict it
+			// immediately so a caller that joins this key afterwards starts
+			// a fresh call instead of racing the still-unfinished delete in
+			// the goroutine above and receiving this abandoned call's
+			// result.
+			if k.calls[key] == c {
+				delete(k.calls, key)
+			}
+			c.cancel()
+		}
+		k.mu.Unlock()
+		return nil, &SubmitError{Op: "SubmitKeyed", Side: SideCaller, Cause: ctx.Err()}
+	case res := <-resultChan:
+		return res.val, res.err
+	}
+}
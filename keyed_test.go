@@ -0,0 +1,211 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSubmitKeyedCoalesces(t *testing.T) {
+	ex := New()
+	go ex.RunLoop(context.Background())
+	k := NewKeyed(ex)
+
+	var calls int32
+	var wg sync.WaitGroup
+	results := make([]interface{}, 8)
+	wg.Add(8)
+	for i := 0; i < 8; i++ {
+		go func(i int) {
+			defer wg.Done()
+			res, err := k.SubmitKeyed(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(10 * time.Millisecond)
+				return 42, nil
+			})
+			if err != nil {
+				t.Fail()
+			}
+			results[i] = res
+		}(i)
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fail()
+	}
+	for _, res := range results {
+		if res != 42 {
+			t.Fail()
+		}
+	}
+}
+
+func TestSubmitKeyedFreshAfterCompletion(t *testing.T) {
+	ex := New()
+	go ex.RunLoop(context.Background())
+	k := NewKeyed(ex)
+
+	res1, err := k.SubmitKeyed(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+		return 1, nil
+	})
+	res2, err2 := k.SubmitKeyed(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+		return 2, nil
+	})
+	if err != nil || err2 != nil {
+		t.Fail()
+	}
+	if res1 != 1 || res2 != 2 {
+		t.Fail()
+	}
+}
+
+func TestSubmitKeyedCancelError(t *testing.T) {
+	ex := New()
+	k := NewKeyed(ex)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := k.SubmitKeyed(ctx, "key", func(ctx context.Context) (interface{}, error) {
+		t.Fail()
+		return nil, nil
+	})
+	var submitErr *SubmitError
+	if !errors.As(err, &submitErr) || submitErr.Op != "SubmitKeyed" || submitErr.Side != SideCaller {
+		t.Fail()
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fail()
+	}
+}
+
+func TestSubmitKeyedCancelDoesNotAffectOthers(t *testing.T) {
+	ex := New()
+	go ex.RunLoop(context.Background())
+	k := NewKeyed(ex)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	f := func(ctx context.Context) (interface{}, error) {
+		once.Do(func() { close(started) })
+		<-release
+		return 42, nil
+	}
+
+	const stayers = 3
+	var wg sync.WaitGroup
+	results := make([]interface{}, stayers)
+	errs := make([]error, stayers)
+	wg.Add(stayers)
+	for i := 0; i < stayers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = k.SubmitKeyed(context.Background(), "key", f)
+		}(i)
+	}
+	<-started // the shared call is now in flight
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	var errA error
+	doneA := make(chan struct{})
+	go func() {
+		defer close(doneA)
+		_, errA = k.SubmitKeyed(ctxA, "key", f)
+	}()
+	time.Sleep(10 * time.Millisecond) // let A join the in-flight call
+	cancelA()
+	<-doneA
+
+	close(release)
+	wg.Wait()
+
+	if !errors.Is(errA, context.Canceled) {
+		t.Fail()
+	}
+	for i := 0; i < stayers; i++ {
+		if errs[i] != nil || results[i] != 42 {
+			t.Fail()
+		}
+	}
+}
+
+// TestSubmitKeyedJoinDuringTeardown is a regression test for a race where a
+// waiter cancelling as the sole member of a call could leave the call
+// reachable in the map just long enough for a brand-new, uncancelable
+// caller to join it and receive the abandoned call's context.Canceled
+// result instead of a fresh invocation of f.
+func TestSubmitKeyedJoinDuringTeardown(t *testing.T) {
+	ex := New()
+	go ex.RunLoop(context.Background())
+	k := NewKeyed(ex)
+
+	for i := 0; i < 20; i++ {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var once sync.Once
+		f := func(ctx context.Context) (interface{}, error) {
+			once.Do(func() { close(started) })
+			select {
+			case <-release:
+				return 42, nil
+			case <-ctx.Done():
+				// Widen the window between the abandoned call observing
+				// cancellation and any stale map entry being cleaned up,
+				// so a regression here is reliably caught rather than
+				// merely possible.
+				time.Sleep(5 * time.Millisecond)
+				return nil, ctx.Err()
+			}
+		}
+
+		ctxA, cancelA := context.WithCancel(context.Background())
+		doneA := make(chan struct{})
+		go func() {
+			defer close(doneA)
+			k.SubmitKeyed(ctxA, "key", f)
+		}()
+		<-started
+		cancelA()
+		<-doneA
+
+		var res interface{}
+		var err error
+		doneB := make(chan struct{})
+		go func() {
+			defer close(doneB)
+			res, err = k.SubmitKeyed(context.Background(), "key", f)
+		}()
+		close(release)
+		<-doneB
+		if err != nil || res != 42 {
+			t.Fail()
+		}
+	}
+}
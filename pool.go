@@ -0,0 +1,135 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// NewPool creates a buffered Executor backed by workers callee goroutines,
+// each running RunLoop until ctx is canceled or the executor is closed. This
+// lets Submit load-balance requests across the workers without changing how
+// callers use the returned Executor.
+//
+// NewPool panics if workers is not positive: a pool with no workers could
+// never drain its Executor, so every Submit against it would block forever.
+func NewPool(ctx context.Context, workers int, bufferLength uint) Executor {
+	ex := NewBuffered(bufferLength)
+	if err := ex.RunPool(ctx, workers); err != nil {
+		panic(err)
+	}
+	return ex
+}
+
+// RunPool spawns workers callee goroutines that each drain e via RunLoop
+// until ctx is canceled or e is closed, and returns once all of them have
+// been started. It turns e into a worker pool without changing the caller
+// API: Submit still returns a single result.
+//
+// RunPool returns an error without spawning any goroutine if workers is not
+// positive, since such a pool could never drain e.
+func (e Executor) RunPool(ctx context.Context, workers int) error {
+	if workers <= 0 {
+		return fmt.Errorf("cgc: RunPool: workers must be positive, got %d", workers)
+	}
+	for i := 0; i < workers; i++ {
+		go e.RunLoop(ctx)
+	}
+	return nil
+}
+
+// PoolStats holds atomic counters describing the state of a pool created
+// with NewPoolWithStats.
+type PoolStats struct {
+	queued    func() int
+	inFlight  int64
+	completed int64
+}
+
+// Queued returns the number of requests currently buffered in the pool's
+// Executor, waiting for a free worker.
+func (s *PoolStats) Queued() int {
+	return s.queued()
+}
+
+// InFlight returns the number of requests currently being executed by a
+// worker.
+func (s *PoolStats) InFlight() int64 {
+	return atomic.LoadInt64(&s.inFlight)
+}
+
+// Completed returns the number of requests the pool has finished executing.
+func (s *PoolStats) Completed() int64 {
+	return atomic.LoadInt64(&s.completed)
+}
+
+// NewPoolWithStats is like NewPool, but also returns a PoolStats that
+// reports queued, in-flight, and completed request counts for the pool.
+//
+// NewPoolWithStats panics if workers is not positive, for the same reason as
+// NewPool.
+func NewPoolWithStats(ctx context.Context, workers int, bufferLength uint) (Executor, *PoolStats) {
+	if workers <= 0 {
+		panic(fmt.Errorf("cgc: NewPoolWithStats: workers must be positive, got %d", workers))
+	}
+	ex := NewBuffered(bufferLength)
+	stats := &PoolStats{
+		queued: func() int { return len(ex) },
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case r, ok := <-ex:
+					if !ok {
+						return
+					}
+					atomic.AddInt64(&stats.inFlight, 1)
+					f := r.Func
+					r.Func = func(ctx context.Context) (interface{}, error) {
+						// Update the counters before RunOneRequest hands the
+						// result to the caller.
+						defer func() {
+							atomic.AddInt64(&stats.inFlight, -1)
+							atomic.AddInt64(&stats.completed, 1)
+						}()
+						return f(ctx)
+					}
+					RunOneRequest(ctx, r)
+				}
+			}
+		}()
+	}
+
+	return ex, stats
+}
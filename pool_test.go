@@ -0,0 +1,99 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestNewPool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ex := NewPool(ctx, 4, 0)
+
+	var wg sync.WaitGroup
+	wg.Add(8)
+	for i := 0; i < 8; i++ {
+		go func(i int) {
+			defer wg.Done()
+			res, err := ex.Submit(context.Background(), func(ctx context.Context) (interface{}, error) {
+				return i, nil
+			})
+			if res != i || err != nil {
+				t.Fail()
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestNewPoolWithStats(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ex, stats := NewPoolWithStats(ctx, 1, 4)
+
+	if stats.InFlight() != 0 || stats.Completed() != 0 {
+		t.Fail()
+	}
+
+	res, err := ex.Submit(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return 42, nil
+	})
+	if res != 42 || err != nil {
+		t.Fail()
+	}
+	if stats.Completed() != 1 {
+		t.Fail()
+	}
+}
+
+func TestRunPoolRejectsNonPositiveWorkers(t *testing.T) {
+	ex := New()
+	if err := ex.RunPool(context.Background(), 0); err == nil {
+		t.Fail()
+	}
+	if err := ex.RunPool(context.Background(), -1); err == nil {
+		t.Fail()
+	}
+}
+
+func TestNewPoolPanicsOnNonPositiveWorkers(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	NewPool(context.Background(), 0, 0)
+}
+
+func TestNewPoolWithStatsPanicsOnNonPositiveWorkers(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	NewPoolWithStats(context.Background(), 0, 0)
+}
@@ -0,0 +1,213 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// priorityItem is one pending request in a PriorityExecutor's queue.
+type priorityItem struct {
+	f          Func
+	ctx        context.Context
+	result     chan<- *result
+	priority   int
+	eligibleAt time.Time
+	index      int
+}
+
+// priorityHeap orders items by eligibleAt first, so that scheduled requests
+// become ready in chronological order, and by priority among items that
+// share the same eligibleAt (in particular the zero time used by plain
+// Submit calls), so that lower priority values run first.
+type priorityHeap []*priorityItem
+
+func (h priorityHeap) Len() int { return len(h) }
+
+func (h priorityHeap) Less(i, j int) bool {
+	if !h[i].eligibleAt.Equal(h[j].eligibleAt) {
+		return h[i].eligibleAt.Before(h[j].eligibleAt)
+	}
+	return h[i].priority < h[j].priority
+}
+
+func (h priorityHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *priorityHeap) Push(x interface{}) {
+	item := x.(*priorityItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *priorityHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+	return item
+}
+
+// PriorityExecutor is a priority- and time-aware alternative to Executor. In
+// place of a plain channel, submitted requests are kept in a heap so that
+// the callee can run the highest-priority ready request first, or defer a
+// request until a scheduled time.
+type PriorityExecutor struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	items priorityHeap
+}
+
+// NewPriority creates a new PriorityExecutor.
+func NewPriority() *PriorityExecutor {
+	p := &PriorityExecutor{}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// Submit submits a request with the given priority and waits for the
+// result. Requests submitted with Submit run ahead of every request
+// submitted with SubmitAt, and among themselves lower priority values run
+// first.
+//
+// This function should be called from the caller goroutine, either ctx or
+// the context at the callee goroutine may cancel the request.
+func (p *PriorityExecutor) Submit(ctx context.Context, priority int, f Func) (interface{}, error) {
+	return p.submit(ctx, "Submit", priority, time.Time{}, f)
+}
+
+// SubmitAt submits a request that does not become eligible to run until at,
+// and waits for the result. This is useful for rate-limited or scheduled
+// work.
+//
+// This function should be called from the caller goroutine, either ctx or
+// the context at the callee goroutine may cancel the request.
+func (p *PriorityExecutor) SubmitAt(ctx context.Context, at time.Time, f Func) (interface{}, error) {
+	return p.submit(ctx, "SubmitAt", 0, at, f)
+}
+
+func (p *PriorityExecutor) submit(ctx context.Context, op string, priority int, at time.Time, f Func) (interface{}, error) {
+	resultChan := make(chan *result, 1)
+	item := &priorityItem{
+		f:          f,
+		ctx:        ctx,
+		result:     resultChan,
+		priority:   priority,
+		eligibleAt: at,
+	}
+
+	p.mu.Lock()
+	heap.Push(&p.items, item)
+	p.cond.Broadcast()
+	p.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		p.mu.Lock()
+		if item.index >= 0 {
+			// Still queued: remove it before it ever runs.
+			heap.Remove(&p.items, item.index)
+			p.mu.Unlock()
+			return nil, &SubmitError{Op: op, Side: SideCaller, Cause: ctx.Err()}
+		}
+		p.mu.Unlock()
+		// Already popped off the heap and handed to RunOneRequest: the
+		// joined context drives f's cancellation from here on, same as
+		// the base Executor. Wait for the real result instead of
+		// returning early and discarding it.
+		res := <-resultChan
+		return res.val, res.err
+	case res := <-resultChan:
+		return res.val, res.err
+	}
+}
+
+// RunOnce executes the next eligible request in the queue, blocking until
+// one is ready, ctx is done, or a request scheduled with SubmitAt becomes
+// due.
+//
+// This function should be called from the callee goroutine, either ctx or
+// the submitter's context may cancel the inner function.
+func (p *PriorityExecutor) RunOnce(ctx context.Context) error {
+	abort := make(chan struct{})
+	defer close(abort)
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.mu.Lock()
+			p.cond.Broadcast()
+			p.mu.Unlock()
+		case <-abort:
+		}
+	}()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return &SubmitError{Op: "RunOnce", Side: SideCallee, Cause: err}
+		}
+		if len(p.items) == 0 {
+			p.cond.Wait()
+			continue
+		}
+		next := p.items[0]
+		if next.eligibleAt.IsZero() || !next.eligibleAt.After(time.Now()) {
+			item := heap.Pop(&p.items).(*priorityItem)
+			p.mu.Unlock()
+			req := &Request{Func: item.f, Context: item.ctx, result: item.result}
+			RunOneRequest(ctx, req)
+			p.mu.Lock()
+			return nil
+		}
+
+		timer := time.AfterFunc(time.Until(next.eligibleAt), func() {
+			p.mu.Lock()
+			p.cond.Broadcast()
+			p.mu.Unlock()
+		})
+		p.cond.Wait()
+		timer.Stop()
+	}
+}
+
+// RunLoop keeps executing requests from the queue until ctx is canceled.
+//
+// This function should be called from the callee goroutine, either ctx or a
+// submitter's context may cancel the inner function.
+func (p *PriorityExecutor) RunLoop(ctx context.Context) error {
+	for {
+		if err := p.RunOnce(ctx); err != nil {
+			return err
+		}
+	}
+}
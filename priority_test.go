@@ -0,0 +1,119 @@
+/*
+   cgc -- Cross Goroutine Calls
+   Copyright (C) 2018 Star Brilliant <coder@poorlab.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a
+   copy of this software and associated documentation files (the "Software"),
+   to deal in the Software without restriction, including without limitation
+   the rights to use, copy, modify, merge, publish, distribute, sublicense,
+   and/or sell copies of the Software, and to permit persons to whom the
+   Software is furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in
+   all copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+   FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+   DEALINGS IN THE SOFTWARE.
+*/
+
+package cgc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPriorityOrdering(t *testing.T) {
+	p := NewPriority()
+	var order []int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	wg.Add(3)
+	for _, priority := range []int{2, 0, 1} {
+		priority := priority
+		go func() {
+			defer wg.Done()
+			p.Submit(context.Background(), priority, func(ctx context.Context) (interface{}, error) {
+				mu.Lock()
+				order = append(order, priority)
+				mu.Unlock()
+				return nil, nil
+			})
+		}()
+	}
+
+	// Give every goroutine a chance to enqueue before the callee starts
+	// draining the queue.
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		if err := p.RunOnce(context.Background()); err != nil {
+			t.Fail()
+		}
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 3 || order[0] != 0 || order[1] != 1 || order[2] != 2 {
+		t.Fail()
+	}
+}
+
+func TestSubmitAt(t *testing.T) {
+	p := NewPriority()
+	go p.RunLoop(context.Background())
+
+	start := time.Now()
+	res, err := p.SubmitAt(context.Background(), start.Add(20*time.Millisecond), func(ctx context.Context) (interface{}, error) {
+		return 42, nil
+	})
+	if res != 42 || err != nil {
+		t.Fail()
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Fail()
+	}
+}
+
+func TestPrioritySubmitCancel(t *testing.T) {
+	p := NewPriority()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := p.Submit(ctx, 0, func(ctx context.Context) (interface{}, error) {
+		t.Fail()
+		return nil, nil
+	})
+	if res != nil || !errors.Is(err, context.Canceled) {
+		t.Fail()
+	}
+	if len(p.items) != 0 {
+		t.Fail()
+	}
+	var submitErr *SubmitError
+	if !errors.As(err, &submitErr) || submitErr.Op != "Submit" || submitErr.Side != SideCaller {
+		t.Fail()
+	}
+}
+
+func TestPriorityRunOnceCancel(t *testing.T) {
+	p := NewPriority()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := p.RunOnce(ctx)
+	var submitErr *SubmitError
+	if !errors.As(err, &submitErr) || submitErr.Op != "RunOnce" || submitErr.Side != SideCallee {
+		t.Fail()
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fail()
+	}
+}